@@ -0,0 +1,164 @@
+package binary
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Asset describes one served file: either a compiled-in Files entry
+// (registered by init below) or a file merged in from a runtime-scanned
+// directory.
+type Asset struct {
+	// Name is the key under which the file is served, e.g.
+	// "nanopi-r4s.dtb".
+	Name string `json:"name"`
+	// Board and SoC are populated from a manifest.yaml entry, when
+	// present, and are empty otherwise.
+	Board string `json:"board,omitempty"`
+	SoC   string `json:"soc,omitempty"`
+	// SHA256 is the hex-encoded checksum of the file content.
+	SHA256 string `json:"sha256"`
+}
+
+// Assets is the registry of served files, keyed by the name they're served
+// under: the compiled-in Files entries plus whatever --dtb-dir,
+// --firmware-dir and --uboot-dir merged into Files, Firmware and UBoot.
+var Assets = map[string]Asset{}
+
+// init registers every compiled-in Files entry (the iPXE binaries and the
+// DTBs) so they show up in /manifest.json alongside anything scanned from
+// the asset directories at startup.
+func init() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for name, content := range Files {
+		sum := sha256.Sum256(content)
+		Assets[name] = Asset{Name: name, SHA256: hex.EncodeToString(sum[:])}
+	}
+}
+
+// manifestEntry is one row of a directory's manifest.yaml, mapping a board
+// name and SoC to the file that implements it. This mirrors the board
+// table maintained by sbc-rockchip style projects.
+type manifestEntry struct {
+	Board string `yaml:"board"`
+	SoC   string `yaml:"soc"`
+	File  string `yaml:"file"`
+}
+
+// LoadAssetsDir scans dir for files with any of exts (e.g. ".dtb", ".bin"),
+// registers each one in Files and Assets, and merges in board/SoC metadata
+// from a manifest.yaml in the same directory, if present. It's intended to
+// be called once at startup for --dtb-dir; --firmware-dir and --uboot-dir
+// go through LoadFirmwareDir and LoadUBootDir instead.
+func LoadAssetsDir(dir string, exts ...string) error {
+	return loadDirInto(dir, Files, exts)
+}
+
+// loadDirInto is the shared implementation behind LoadAssetsDir and the
+// --uboot-dir/--firmware-dir loaders in boards.go: it scans dir for files
+// with any of exts, registers each one in dest and in the Assets registry,
+// and merges in board/SoC metadata from a manifest.yaml in the same
+// directory, if present.
+func loadDirInto(dir string, dest map[string][]byte, exts []string) error {
+	manifest, err := loadManifest(filepath.Join(dir, "manifest.yaml"))
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading asset dir %q: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !hasAnyExt(entry.Name(), exts) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading asset %q: %w", path, err)
+		}
+
+		sum := sha256.Sum256(content)
+		asset := Asset{
+			Name:   entry.Name(),
+			SHA256: hex.EncodeToString(sum[:]),
+		}
+		if m, ok := manifest[entry.Name()]; ok {
+			asset.Board = m.Board
+			asset.SoC = m.SoC
+		}
+
+		registryMu.Lock()
+		dest[asset.Name] = content
+		Assets[asset.Name] = asset
+		registryMu.Unlock()
+	}
+
+	return nil
+}
+
+func loadManifest(path string) (map[string]manifestEntry, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest %q: %w", path, err)
+	}
+
+	var rows []manifestEntry
+	if err := yaml.Unmarshal(b, &rows); err != nil {
+		return nil, fmt.Errorf("parsing manifest %q: %w", path, err)
+	}
+
+	byFile := make(map[string]manifestEntry, len(rows))
+	for _, row := range rows {
+		byFile[row.File] = row
+	}
+	return byFile, nil
+}
+
+func hasAnyExt(name string, exts []string) bool {
+	for _, ext := range exts {
+		if strings.EqualFold(filepath.Ext(name), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// ManifestJSON renders the current Assets registry as the JSON body for the
+// /manifest.json endpoint, so provisioning clients can discover what boards
+// this server can serve without guessing file names.
+func ManifestJSON() ([]byte, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return json.Marshal(Assets)
+}
+
+// ManifestHandler serves ManifestJSON. A server's mux registers this at
+// "/manifest.json" alongside its TFTP/HTTP binary-serving routes.
+func ManifestHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := ManifestJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,62 @@
+package binary
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHasAnyExt(t *testing.T) {
+	cases := []struct {
+		name string
+		exts []string
+		want bool
+	}{
+		{"nanopi-r4s.dtb", []string{".dtb"}, true},
+		{"nanopi-r4s.DTB", []string{".dtb"}, true},
+		{"start.elf", []string{".dtb", ".bin"}, false},
+		{"start.elf", []string{".dtb", ".elf"}, true},
+	}
+
+	for _, tc := range cases {
+		if got := hasAnyExt(tc.name, tc.exts); got != tc.want {
+			t.Errorf("hasAnyExt(%q, %v) = %v, want %v", tc.name, tc.exts, got, tc.want)
+		}
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manifest.yaml")
+	body := `
+- board: NanoPi R4S
+  soc: rk3399
+  file: rk3399-nanopi-r4s.dtb
+`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+
+	manifest, err := loadManifest(path)
+	if err != nil {
+		t.Fatalf("loadManifest() error: %v", err)
+	}
+
+	entry, ok := manifest["rk3399-nanopi-r4s.dtb"]
+	if !ok {
+		t.Fatalf("loadManifest() missing entry for rk3399-nanopi-r4s.dtb: %+v", manifest)
+	}
+	if entry.Board != "NanoPi R4S" || entry.SoC != "rk3399" {
+		t.Errorf("loadManifest() entry = %+v, want Board=NanoPi R4S SoC=rk3399", entry)
+	}
+}
+
+func TestLoadManifestMissingFile(t *testing.T) {
+	manifest, err := loadManifest(filepath.Join(t.TempDir(), "does-not-exist.yaml"))
+	if err != nil {
+		t.Fatalf("loadManifest() on a missing file: want nil error, got %v", err)
+	}
+	if manifest != nil {
+		t.Errorf("loadManifest() on a missing file: want nil manifest, got %+v", manifest)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"bytes"
 	_ "embed"
 	"errors"
+	"fmt"
+	"sync"
 )
 
 // IpxeEFI is the UEFI iPXE binary for x86 architectures.
@@ -63,6 +65,51 @@ var Files = map[string][]byte{
 
 var ErrPatchTooLong = errors.New("patch string is too long")
 
+// ErrFileNotFound is returned by PatchedFile when name isn't a key in Files.
+var ErrFileNotFound = errors.New("file not found")
+
+// registryMu guards Files, Assets, UBoot and Firmware. They start out
+// read-only (populated once at init from //go:embed data), but
+// LoadAssetsDir/LoadUBootDir/LoadFirmwareDir write into them at startup and
+// ipxebuild.BuildAndRegister writes into Files after a build completes
+// while request-serving code may already be reading them, so every access
+// to these maps holds registryMu. Code outside this package goes through
+// the Get*/Set* helpers (GetFile/SetFile below, GetUBoot/GetFirmware in
+// boards.go) rather than indexing the maps directly.
+var registryMu sync.RWMutex
+
+// GetFile returns the named entry from Files.
+func GetFile(name string) ([]byte, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	content, ok := Files[name]
+	return content, ok
+}
+
+// SetFile registers content under name in Files, overwriting any existing
+// entry.
+func SetFile(name string, content []byte) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	Files[name] = content
+}
+
+// PatchedFile looks up name in Files and returns its content with patch
+// applied by Patch, for callers that resolve a per-request patch (see
+// package resolver) instead of using the single global patch value. As with
+// Patch, the result is a fresh copy only when a patch was applied; otherwise
+// it is the stored content itself.
+func PatchedFile(name string, patch []byte) ([]byte, error) {
+	content, ok := GetFile(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, name)
+	}
+
+	return Patch(content, patch)
+}
+
 // Replace the magic string in the content with the patch. Returns the original content
 // when the patch is empty or the magic string is not found, and returns an error when
 // the patch is too long.
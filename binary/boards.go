@@ -0,0 +1,130 @@
+package binary
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"text/template"
+)
+
+// UBoot holds U-Boot binaries registered at runtime via --uboot-dir, keyed
+// by the file name under which they're served (e.g. "rk3588-u-boot.bin").
+// Use GetUBoot rather than indexing this directly: LoadUBootDir writes it
+// under registryMu, same as Files.
+var UBoot = map[string][]byte{}
+
+// Firmware holds board firmware blobs registered at runtime via
+// --firmware-dir, keyed by the file name under which they're served (e.g.
+// "start.elf", "bootcode.bin", "idbloader.img"). Use GetFirmware rather
+// than indexing this directly: LoadFirmwareDir writes it under registryMu,
+// same as Files.
+var Firmware = map[string][]byte{}
+
+// LoadUBootDir scans dir for U-Boot images and registers them in UBoot and
+// Assets, same as LoadAssetsDir does for Files.
+func LoadUBootDir(dir string) error {
+	return loadDirInto(dir, UBoot, []string{".bin", ".img"})
+}
+
+// LoadFirmwareDir scans dir for firmware blobs and registers them in
+// Firmware and Assets, same as LoadAssetsDir does for Files.
+func LoadFirmwareDir(dir string) error {
+	return loadDirInto(dir, Firmware, []string{".bin", ".elf", ".dat", ".img"})
+}
+
+// GetUBoot returns the named entry from UBoot. Like GetFile, it goes
+// through registryMu rather than indexing UBoot directly, since
+// LoadUBootDir writes it concurrently with any request-serving reads.
+func GetUBoot(name string) ([]byte, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	content, ok := UBoot[name]
+	return content, ok
+}
+
+// GetFirmware returns the named entry from Firmware. Like GetFile, it goes
+// through registryMu rather than indexing Firmware directly, since
+// LoadFirmwareDir writes it concurrently with any request-serving reads.
+func GetFirmware(name string) ([]byte, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	content, ok := Firmware[name]
+	return content, ok
+}
+
+// BoardInstallOptions carries the per-MAC choices needed to netboot a
+// specific ARM SBC through U-Boot: which U-Boot image to chainload, which
+// DTB to pass it, and which Raspberry Pi firmware set (if any) the board
+// needs before U-Boot even runs.
+type BoardInstallOptions struct {
+	// MAC is the requesting client, used to name the generated boot.scr
+	// the way U-Boot's tftpboot/pxe commands expect.
+	MAC net.HardwareAddr
+	// UBootPath is the served path of the U-Boot image to chainload,
+	// a key in UBoot.
+	UBootPath string
+	// DTBPath is the served path of the DTB to pass to the kernel, a
+	// key in Files.
+	DTBPath string
+	// RPiFirmwarePath is the served path of the Raspberry Pi firmware
+	// directory prefix (start.elf, bootcode.bin, ...), empty for
+	// boards that don't need it.
+	RPiFirmwarePath string
+}
+
+var bootScrTemplate = template.Must(template.New("boot.scr").Parse(
+	`setenv bootfile {{.UBootPath}}
+setenv fdtfile {{.DTBPath}}
+tftpboot ${kernel_addr_r} ${bootfile}
+tftpboot ${fdt_addr_r} ${fdtfile}
+bootefi ${kernel_addr_r} ${fdt_addr_r}
+`))
+
+// BootScrFilename returns the PXELinux/U-Boot distro-boot convention
+// filename for mac's boot.scr: "01-" followed by the lowercase, dash
+// separated hardware address, e.g. "01-aa-bb-cc-dd-ee-ff". This is the
+// filename U-Boot's pxe/tftpboot commands look for before falling back to
+// a default, so per-board boot.scr content must be served under it.
+func BootScrFilename(mac net.HardwareAddr) string {
+	parts := make([]string, 0, len(mac)+1)
+	parts = append(parts, "01")
+	for _, b := range mac {
+		parts = append(parts, fmt.Sprintf("%02x", b))
+	}
+	return strings.Join(parts, "-")
+}
+
+// GenerateBootScr renders the mkimage source for a boot.scr tailored to
+// opts, and the filename it must be served under, so U-Boot's distro boot
+// scripts find the right kernel stage and DTB for the requesting board.
+func GenerateBootScr(opts BoardInstallOptions) (filename string, content []byte, err error) {
+	var buf bytes.Buffer
+	if err := bootScrTemplate.Execute(&buf, opts); err != nil {
+		return "", nil, fmt.Errorf("generating boot.scr for %s: %w", opts.MAC, err)
+	}
+	return BootScrFilename(opts.MAC), buf.Bytes(), nil
+}
+
+// GenerateConfigTxt renders the config.txt the Raspberry Pi firmware reads
+// before booting U-Boot, pointing it at opts.UBootPath as the kernel to
+// load. When opts.RPiFirmwarePath is set, it's passed through as os_prefix
+// so the firmware looks for that kernel (and any other files it needs)
+// under the board-specific firmware directory instead of the TFTP root.
+func GenerateConfigTxt(opts BoardInstallOptions) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "[all]\nkernel=%s\narm_64bit=1\nenable_uart=1\n", opts.UBootPath)
+	if opts.RPiFirmwarePath != "" {
+		fmt.Fprintf(&buf, "os_prefix=%s\n", opts.RPiFirmwarePath)
+	}
+	return buf.Bytes()
+}
+
+// GenerateCmdlineTxt renders the cmdline.txt paired with config.txt above.
+// U-Boot ignores most of it, but the Raspberry Pi firmware requires the
+// file to exist.
+func GenerateCmdlineTxt(opts BoardInstallOptions) []byte {
+	return []byte("console=serial0,115200 console=tty1\n")
+}
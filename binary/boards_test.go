@@ -0,0 +1,55 @@
+package binary
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestBootScrFilename(t *testing.T) {
+	mac, err := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	if err != nil {
+		t.Fatalf("parsing MAC: %v", err)
+	}
+
+	got := BootScrFilename(mac)
+	want := "01-aa-bb-cc-dd-ee-ff"
+	if got != want {
+		t.Errorf("BootScrFilename(%s) = %q, want %q", mac, got, want)
+	}
+}
+
+func TestGenerateBootScr(t *testing.T) {
+	mac, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	opts := BoardInstallOptions{
+		MAC:       mac,
+		UBootPath: "rk3588-u-boot.bin",
+		DTBPath:   "rk3588-rock-5b.dtb",
+	}
+
+	filename, content, err := GenerateBootScr(opts)
+	if err != nil {
+		t.Fatalf("GenerateBootScr() error: %v", err)
+	}
+	if filename != "01-aa-bb-cc-dd-ee-ff" {
+		t.Errorf("GenerateBootScr() filename = %q, want %q", filename, "01-aa-bb-cc-dd-ee-ff")
+	}
+	if !strings.Contains(string(content), "setenv bootfile rk3588-u-boot.bin") {
+		t.Errorf("GenerateBootScr() content missing bootfile line: %s", content)
+	}
+	if !strings.Contains(string(content), "setenv fdtfile rk3588-rock-5b.dtb") {
+		t.Errorf("GenerateBootScr() content missing fdtfile line: %s", content)
+	}
+}
+
+func TestGenerateConfigTxt(t *testing.T) {
+	withFirmware := GenerateConfigTxt(BoardInstallOptions{UBootPath: "u-boot.bin", RPiFirmwarePath: "rpi4"})
+	if !strings.Contains(string(withFirmware), "os_prefix=rpi4\n") {
+		t.Errorf("GenerateConfigTxt() with RPiFirmwarePath missing os_prefix: %s", withFirmware)
+	}
+
+	withoutFirmware := GenerateConfigTxt(BoardInstallOptions{UBootPath: "u-boot.bin"})
+	if strings.Contains(string(withoutFirmware), "os_prefix") {
+		t.Errorf("GenerateConfigTxt() without RPiFirmwarePath should omit os_prefix: %s", withoutFirmware)
+	}
+}
@@ -0,0 +1,42 @@
+package binary
+
+import "flag"
+
+// DirFlags is the CLI-facing configuration for the runtime-scanned asset
+// directories: --dtb-dir, --firmware-dir and --uboot-dir. A server's
+// startup code registers these flags, parses them, and calls Load once
+// before accepting requests.
+type DirFlags struct {
+	DTBDir      string
+	FirmwareDir string
+	UBootDir    string
+}
+
+// RegisterFlags registers --dtb-dir, --firmware-dir and --uboot-dir on fs.
+func (d *DirFlags) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&d.DTBDir, "dtb-dir", "", "directory of DTB files to scan and serve alongside the built-in ones")
+	fs.StringVar(&d.FirmwareDir, "firmware-dir", "", "directory of board firmware blobs to scan and serve")
+	fs.StringVar(&d.UBootDir, "uboot-dir", "", "directory of U-Boot images to scan and serve")
+}
+
+// Load scans whichever of d's directories are set, merging their contents
+// into Files/UBoot/Firmware and Assets. It's a no-op for any directory left
+// empty.
+func (d *DirFlags) Load() error {
+	if d.DTBDir != "" {
+		if err := LoadAssetsDir(d.DTBDir, ".dtb"); err != nil {
+			return err
+		}
+	}
+	if d.FirmwareDir != "" {
+		if err := LoadFirmwareDir(d.FirmwareDir); err != nil {
+			return err
+		}
+	}
+	if d.UBootDir != "" {
+		if err := LoadUBootDir(d.UBootDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
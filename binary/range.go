@@ -0,0 +1,28 @@
+package binary
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+)
+
+// ServeFile serves the named entry from Files over HTTP via
+// http.ServeContent, which implements byte-range requests for us. This
+// matters for ipxe.iso: iPXE's sanboot wants to seek around a single large
+// blob instead of downloading it whole, especially over slow links.
+func ServeFile(w http.ResponseWriter, r *http.Request, name string) {
+	content, ok := GetFile(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	http.ServeContent(w, r, name, buildTime, bytes.NewReader(content))
+}
+
+// buildTime stands in for the embedded files' modification time, which
+// //go:embed doesn't preserve. It's the Unix epoch, which ServeContent
+// treats as "unknown": no Last-Modified header is sent and
+// If-Modified-Since/If-Unmodified-Since are ignored, while plain Range
+// requests are still honored.
+var buildTime = time.Unix(0, 0)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/equinix-labs/otel-init-go/otelinit"
 	"github.com/rpardini/tinkerbell-ipxedust"
+	"github.com/rpardini/tinkerbell-ipxedust/ipxecmd"
 )
 
 func main() {
@@ -20,6 +21,17 @@ func main() {
 
 	ctx, done := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGHUP, syscall.SIGTERM)
 	defer done()
+
+	// ipxebuild is handled separately from the server: it's a one-shot
+	// local build invocation and doesn't need otel wired up.
+	if len(os.Args) > 1 && os.Args[1] == "ipxebuild" {
+		if err := ipxecmd.RunIpxebuild(ctx, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitCode = 1
+		}
+		return
+	}
+
 	ctx, otelShutdown := otelinit.InitOpenTelemetry(ctx, "github.com/rpardini/tinkerbell-ipxedust")
 	defer otelShutdown(ctx)
 
@@ -0,0 +1,50 @@
+package ipxebuild
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestScriptHashIsStableAndDistinct(t *testing.T) {
+	a := scriptHash([]byte("#!ipxe\nchain http://example/a.ipxe\n"))
+	b := scriptHash([]byte("#!ipxe\nchain http://example/a.ipxe\n"))
+	c := scriptHash([]byte("#!ipxe\nchain http://example/b.ipxe\n"))
+
+	if a != b {
+		t.Errorf("scriptHash() not stable across calls for the same script: %q != %q", a, b)
+	}
+	if a == c {
+		t.Errorf("scriptHash() collided for two different scripts")
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "somehash")
+
+	want := make(map[string][]byte, len(targets))
+	for name := range targets {
+		want[name] = []byte("content-of-" + name)
+	}
+
+	if err := writeCache(dir, want); err != nil {
+		t.Fatalf("writeCache() error: %v", err)
+	}
+
+	got, err := readCache(dir)
+	if err != nil {
+		t.Fatalf("readCache() error: %v", err)
+	}
+
+	for name, content := range want {
+		if !bytes.Equal(got[name], content) {
+			t.Errorf("readCache()[%q] = %q, want %q", name, got[name], content)
+		}
+	}
+}
+
+func TestReadCacheMissOnEmptyDir(t *testing.T) {
+	if _, err := readCache(filepath.Join(t.TempDir(), "never-written")); err == nil {
+		t.Error("readCache() on an empty/nonexistent dir: want error, got nil")
+	}
+}
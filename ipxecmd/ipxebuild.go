@@ -0,0 +1,50 @@
+// Package ipxecmd wires ipxebuild's flag parsing to the cmd/main.go
+// subcommand dispatch, kept separate from package ipxebuild so that
+// package stays import-cycle-free from anything under cmd/.
+package ipxecmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rpardini/tinkerbell-ipxedust/ipxebuild"
+)
+
+// RunIpxebuild implements `ipxedust ipxebuild`, compiling iPXE with a
+// user-supplied script embedded and printing the names of the resulting
+// artifacts (the binary.Files keys they're served under).
+func RunIpxebuild(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("ipxebuild", flag.ContinueOnError)
+	scriptPath := fs.String("script", "", "path to the iPXE script to embed via EMBED=")
+	sourceDir := fs.String("ipxe-source-dir", "", "path to an iPXE source tree checkout (not vendored by this tree; bring your own checkout of github.com/ipxe/ipxe)")
+	cacheDir := fs.String("cache-dir", "", "directory to cache built artifacts in, keyed by script hash")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *scriptPath == "" || *sourceDir == "" {
+		return fmt.Errorf("ipxebuild: --script and --ipxe-source-dir are required")
+	}
+
+	script, err := os.ReadFile(*scriptPath)
+	if err != nil {
+		return fmt.Errorf("reading script %q: %w", *scriptPath, err)
+	}
+
+	artifacts, err := ipxebuild.Build(ctx, ipxebuild.Options{
+		Script:        script,
+		IpxeSourceDir: *sourceDir,
+		CacheDir:      *cacheDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	for name := range artifacts {
+		fmt.Fprintln(os.Stdout, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+// Package proxydhcp implements a minimal ProxyDHCP / DHCPv6 responder: it
+// only answers PXE/iPXE option requests, leaving lease assignment to
+// whatever DHCP infrastructure already exists on the network. This lets
+// ipxedust be dropped into networks that already run DHCP, matching the
+// two-stage boot flow used by projects like Smee and Talos.
+package proxydhcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv4/server4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/dhcpv6/server6"
+	"github.com/insomniacslk/dhcp/iana"
+
+	"github.com/rpardini/tinkerbell-ipxedust/binary"
+)
+
+// userClassIPXE is the DHCP user-class iPXE sets on its own requests once
+// it's running, so the proxy can tell a BIOS/UEFI firmware's first request
+// apart from iPXE's second-stage request and hand back an HTTP URL instead
+// of a TFTP one.
+const userClassIPXE = "iPXE"
+
+// archFilenames maps the client architecture reported in DHCP option 93 to
+// the binary.Files entry that architecture should chainload.
+var archFilenames = map[iana.Arch]string{
+	iana.INTEL_X86PC: "undionly.kpxe",
+	iana.EFI_BC:      "ipxe.efi",
+	iana.EFI_X86_64:  "ipxe.efi",
+	iana.EFI_ARM64:   "snp.efi",
+}
+
+// defaultListenAddr is the standard ProxyDHCP port, used when Config
+// doesn't set ListenAddr, so the responder doesn't collide with a real
+// DHCP server on :67.
+const defaultListenAddr = ":4011"
+
+// defaultListenAddr6 is the standard DHCPv6 server port. Real DHCPv6
+// clients send to :547, which is why this needs its own default instead of
+// sharing defaultListenAddr with the v4 responder.
+const defaultListenAddr6 = ":547"
+
+// Config configures the proxy responder.
+type Config struct {
+	// ListenAddr is the UDP address to listen on for DHCPv4. Defaults
+	// to defaultListenAddr when empty.
+	ListenAddr string
+	// ListenAddr6 is the UDP address to listen on for DHCPv6. Defaults
+	// to defaultListenAddr6 when empty.
+	ListenAddr6 string
+	// HTTPBaseURL is prepended to binary.Files names when responding
+	// to a client that's already running iPXE (user-class "iPXE"), so
+	// the second stage fetches over HTTP instead of TFTP.
+	HTTPBaseURL string
+	// TFTPServerIP is handed back as the next-server for the initial,
+	// non-iPXE request.
+	TFTPServerIP net.IP
+}
+
+// Server is a ProxyDHCP (v4) and DHCPv6 responder.
+type Server struct {
+	cfg Config
+}
+
+// New returns a Server for cfg.
+func New(cfg Config) *Server {
+	return &Server{cfg: cfg}
+}
+
+// ListenAndServe runs the DHCPv4 ProxyDHCP responder until ctx is canceled.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	addr, err := resolveListenAddr(s.cfg.ListenAddr, defaultListenAddr)
+	if err != nil {
+		return fmt.Errorf("resolving proxydhcp v4 listen address: %w", err)
+	}
+
+	srv, err := server4.NewServer("", addr, s.handle4)
+	if err != nil {
+		return fmt.Errorf("starting proxydhcp v4 server: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	return srv.Serve()
+}
+
+// ListenAndServe6 runs the DHCPv6 responder until ctx is canceled.
+func (s *Server) ListenAndServe6(ctx context.Context) error {
+	addr, err := resolveListenAddr(s.cfg.ListenAddr6, defaultListenAddr6)
+	if err != nil {
+		return fmt.Errorf("resolving proxydhcp v6 listen address: %w", err)
+	}
+
+	srv, err := server6.NewServer("", addr, s.handle6)
+	if err != nil {
+		return fmt.Errorf("starting proxydhcp v6 server: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	return srv.Serve()
+}
+
+// resolveListenAddr resolves addr, falling back to fallback when addr is
+// unset.
+func resolveListenAddr(addr, fallback string) (*net.UDPAddr, error) {
+	if addr == "" {
+		addr = fallback
+	}
+	return net.ResolveUDPAddr("udp", addr)
+}
+
+// handle4 answers only PXE boot requests, ignoring anything that doesn't
+// request a boot file name (option 67 in its parameter request list) and
+// report a known client architecture (option 93), so a real DHCP server on
+// the same network remains the authority for leases.
+func (s *Server) handle4(conn net.PacketConn, peer net.Addr, req *dhcpv4.DHCPv4) {
+	if req.OpCode != dhcpv4.OpcodeBootRequest || !req.IsOptionRequested(dhcpv4.OptionBootfileName) {
+		return
+	}
+
+	arch, err := clientArch(req)
+	if err != nil {
+		return
+	}
+
+	filename, ok := archFilenames[arch]
+	if !ok {
+		return
+	}
+
+	if req.GetOneOption(dhcpv4.OptionClassIdentifier) != nil && isIPXEUserClass(req) {
+		filename = fmt.Sprintf("%s/%s", s.cfg.HTTPBaseURL, filename)
+	} else if _, ok := binary.GetFile(filename); !ok {
+		return
+	}
+
+	resp, err := dhcpv4.NewReplyFromRequest(req,
+		dhcpv4.WithMessageType(dhcpv4.MessageTypeAck),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(s.cfg.TFTPServerIP)),
+		dhcpv4.WithOption(dhcpv4.OptBootFileName(filename)),
+	)
+	if err != nil {
+		return
+	}
+	resp.ServerIPAddr = s.cfg.TFTPServerIP
+
+	_, _ = conn.WriteTo(resp.ToBytes(), peer)
+}
+
+// handle6 is the DHCPv6 counterpart of handle4, answering only
+// information-request/solicit messages carrying the PXE client option.
+// Unlike handle4 it doesn't pick a file by architecture: every answer
+// points at HTTPBaseURL/ipxe.efi.
+func (s *Server) handle6(conn net.PacketConn, peer net.Addr, req dhcpv6.DHCPv6) {
+	msg, err := req.GetInnerMessage()
+	if err != nil {
+		return
+	}
+
+	if msg.MessageType != dhcpv6.MessageTypeSolicit && msg.MessageType != dhcpv6.MessageTypeInformationRequest {
+		return
+	}
+
+	if msg.GetOneOption(dhcpv6.OptionClientArchType) == nil {
+		return
+	}
+
+	resp, err := dhcpv6.NewReplyFromMessage(msg)
+	if err != nil {
+		return
+	}
+	resp.AddOption(dhcpv6.OptBootFileURL(fmt.Sprintf("%s/ipxe.efi", s.cfg.HTTPBaseURL)))
+
+	_, _ = conn.WriteTo(resp.ToBytes(), peer)
+}
+
+// clientArch extracts the client architecture from DHCP option 93.
+func clientArch(req *dhcpv4.DHCPv4) (iana.Arch, error) {
+	opt := req.GetOneOption(dhcpv4.OptionClientSystemArchitectureType)
+	if opt == nil {
+		return 0, fmt.Errorf("no client architecture option present")
+	}
+
+	archs, err := dhcpv4.ParseClientArchType(opt)
+	if err != nil || len(archs) == 0 {
+		return 0, fmt.Errorf("parsing client architecture: %w", err)
+	}
+
+	return archs[0], nil
+}
+
+// isIPXEUserClass reports whether req carries the iPXE user-class, meaning
+// this is the second-stage request made by iPXE itself rather than the
+// firmware's initial PXE request.
+func isIPXEUserClass(req *dhcpv4.DHCPv4) bool {
+	opt := req.GetOneOption(dhcpv4.OptionUserClassInformation)
+	if opt == nil {
+		return false
+	}
+
+	return string(opt) == userClassIPXE
+}
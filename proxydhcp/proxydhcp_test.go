@@ -0,0 +1,76 @@
+package proxydhcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+func TestResolveListenAddr(t *testing.T) {
+	cases := []struct {
+		name     string
+		addr     string
+		fallback string
+		want     string
+	}{
+		{"explicit v4", "127.0.0.1:4011", defaultListenAddr, "127.0.0.1:4011"},
+		{"v4 default", "", defaultListenAddr, defaultListenAddr},
+		{"v6 default", "", defaultListenAddr6, defaultListenAddr6},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveListenAddr(tc.addr, tc.fallback)
+			if err != nil {
+				t.Fatalf("resolveListenAddr(%q, %q) error: %v", tc.addr, tc.fallback, err)
+			}
+			want, err := net.ResolveUDPAddr("udp", tc.want)
+			if err != nil {
+				t.Fatalf("resolving want addr: %v", err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("resolveListenAddr(%q, %q) = %s, want %s", tc.addr, tc.fallback, got, want)
+			}
+		})
+	}
+}
+
+func newDHCPv4Request(t *testing.T, opts ...dhcpv4.Modifier) *dhcpv4.DHCPv4 {
+	t.Helper()
+	req, err := dhcpv4.NewDiscovery(net.HardwareAddr{0, 1, 2, 3, 4, 5}, opts...)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	return req
+}
+
+func TestClientArch(t *testing.T) {
+	req := newDHCPv4Request(t, dhcpv4.WithClientArch(iana.EFI_X86_64))
+
+	arch, err := clientArch(req)
+	if err != nil {
+		t.Fatalf("clientArch() error: %v", err)
+	}
+	if arch != iana.EFI_X86_64 {
+		t.Errorf("clientArch() = %v, want %v", arch, iana.EFI_X86_64)
+	}
+
+	noArch := newDHCPv4Request(t)
+	if _, err := clientArch(noArch); err == nil {
+		t.Error("clientArch() on a request with no arch option: want error, got nil")
+	}
+}
+
+func TestIsIPXEUserClass(t *testing.T) {
+	ipxeReq := newDHCPv4Request(t, dhcpv4.WithUserClass([]byte(userClassIPXE), false))
+	if !isIPXEUserClass(ipxeReq) {
+		t.Error("isIPXEUserClass() = false, want true for an iPXE user-class request")
+	}
+
+	plainReq := newDHCPv4Request(t)
+	if isIPXEUserClass(plainReq) {
+		t.Error("isIPXEUserClass() = true, want false for a request with no user-class option")
+	}
+}
@@ -0,0 +1,62 @@
+package resolver
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Config is the CLI-facing configuration for the three backends the
+// original request asked for: a static map file, an HTTP callback URL, or
+// a gRPC endpoint. Exactly one of StaticMapPath, HTTPCallbackURL, or
+// GRPCTarget should be set. Build's result is passed as the resolver
+// argument to ResolvePatchedFile, the per-request call a TFTP/HTTP handler
+// makes instead of binary.PatchedFile.
+type Config struct {
+	StaticMapPath   string
+	HTTPCallbackURL string
+	GRPCTarget      string
+	GRPCMethod      string
+}
+
+// RegisterFlags registers the --script-resolver-* flags described by the
+// original request on fs.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.StaticMapPath, "script-resolver-static-map", "", "path to a JSON static script resolver map")
+	fs.StringVar(&c.HTTPCallbackURL, "script-resolver-http-url", "", "URL of an HTTP script resolver callback")
+	fs.StringVar(&c.GRPCTarget, "script-resolver-grpc-target", "", "dial target of a gRPC script resolver service")
+	fs.StringVar(&c.GRPCMethod, "script-resolver-grpc-method", "/tinkerbell.resolver.v1.Resolver/Resolve", "fully qualified RPC method to call on --script-resolver-grpc-target")
+}
+
+// Build constructs the ScriptResolver selected by c, wrapped in a
+// CachingResolver, or returns (nil, nil) when no backend is configured.
+func (c *Config) Build(ctx context.Context) (ScriptResolver, error) {
+	var next ScriptResolver
+
+	switch {
+	case c.GRPCTarget != "":
+		conn, err := grpc.NewClient(c.GRPCTarget, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, fmt.Errorf("dialing script resolver grpc target %q: %w", c.GRPCTarget, err)
+		}
+		next = NewGRPCResolver(conn, c.GRPCMethod)
+
+	case c.HTTPCallbackURL != "":
+		next = NewHTTPResolver(c.HTTPCallbackURL, nil)
+
+	case c.StaticMapPath != "":
+		r, err := NewStaticResolver(c.StaticMapPath)
+		if err != nil {
+			return nil, err
+		}
+		next = r
+
+	default:
+		return nil, nil
+	}
+
+	return NewCachingResolver(next), nil
+}
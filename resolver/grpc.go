@@ -0,0 +1,76 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// grpcJSONCodecName is registered with google.golang.org/grpc's encoding
+// registry below so GRPCResolver can call a resolution RPC without
+// depending on generated protobuf stubs for it: the request/response shape
+// is just resolveRequest/resolveResponse marshaled as JSON over the normal
+// gRPC/HTTP2 transport, via grpc.CallContentSubtype.
+const grpcJSONCodecName = "json"
+
+func init() {
+	encoding.RegisterCodec(grpcJSONCodec{})
+}
+
+type grpcJSONCodec struct{}
+
+func (grpcJSONCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (grpcJSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (grpcJSONCodec) Name() string                       { return grpcJSONCodecName }
+
+// resolveRequest/resolveResponse are the wire types exchanged with the
+// resolver service's Resolve RPC.
+type resolveRequest struct {
+	MAC       string `json:"mac"`
+	IP        string `json:"ip"`
+	UserClass string `json:"user_class"`
+	Arch      string `json:"arch"`
+}
+
+type resolveResponse struct {
+	Patch []byte `json:"patch"`
+	Found bool   `json:"found"`
+}
+
+// GRPCResolver resolves patches by calling a Resolve RPC over an existing
+// gRPC connection, for operators who already run a Tinkerbell-adjacent
+// service that can answer resolution requests faster or more securely than
+// a plain HTTP callback.
+type GRPCResolver struct {
+	conn   *grpc.ClientConn
+	method string // fully-qualified RPC method, e.g. "/resolver.Resolver/Resolve"
+}
+
+// NewGRPCResolver wraps an already-dialed conn. method is the fully
+// qualified RPC name to invoke for every resolution, e.g.
+// "/tinkerbell.resolver.v1.Resolver/Resolve".
+func NewGRPCResolver(conn *grpc.ClientConn, method string) *GRPCResolver {
+	return &GRPCResolver{conn: conn, method: method}
+}
+
+// Resolve implements ScriptResolver.
+func (r *GRPCResolver) Resolve(ctx context.Context, req Request) ([]byte, error) {
+	in := resolveRequest{
+		MAC:       req.MAC.String(),
+		IP:        req.IP.String(),
+		UserClass: req.UserClass,
+		Arch:      req.Arch,
+	}
+
+	var out resolveResponse
+	if err := r.conn.Invoke(ctx, r.method, &in, &out, grpc.CallContentSubtype(grpcJSONCodecName)); err != nil {
+		return nil, fmt.Errorf("grpc script resolver: %w", err)
+	}
+	if !out.Found {
+		return nil, nil
+	}
+	return out.Patch, nil
+}
@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTPResolver resolves patches by POSTing the request details to a
+// configured callback URL, such as a Tinkerbell workflow service, and using
+// the response body as the patch. It's meant for fleets where the boot
+// script depends on state that only lives outside ipxedust, e.g. a Hardware
+// object's current workflow action.
+type HTTPResolver struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPResolver returns an HTTPResolver that calls url for every
+// resolution. client may be nil, in which case http.DefaultClient is used.
+func NewHTTPResolver(url string, client *http.Client) *HTTPResolver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPResolver{url: url, client: client}
+}
+
+// httpResolveRequest is the JSON body POSTed to the callback URL.
+type httpResolveRequest struct {
+	MAC       string `json:"mac"`
+	IP        string `json:"ip"`
+	UserClass string `json:"user_class"`
+	Arch      string `json:"arch"`
+}
+
+// Resolve implements ScriptResolver. A 204 No Content response means no
+// override applies and the server should fall back to its default patch.
+func (r *HTTPResolver) Resolve(ctx context.Context, req Request) ([]byte, error) {
+	body, err := json.Marshal(httpResolveRequest{
+		MAC:       req.MAC.String(),
+		IP:        req.IP.String(),
+		UserClass: req.UserClass,
+		Arch:      req.Arch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling resolve request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building resolve request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("calling script resolver %q: %w", r.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("script resolver %q returned status %d", r.url, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,100 @@
+// Package resolver provides pluggable, per-client resolution of the iPXE
+// patch that should be applied to a served binary. It lets operators route
+// each requesting Hardware to its own boot script instead of relying on a
+// single global patch value.
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// Request describes the client asking for an iPXE binary. It carries
+// everything a ScriptResolver needs to decide which patch to hand back.
+type Request struct {
+	// BinaryName is the binary.Files key being requested, e.g.
+	// "undionly.kpxe". CachingResolver keys its cache off this plus
+	// the other Request fields, so it never has to call the wrapped
+	// resolver to find out whether a request is a cache hit.
+	BinaryName string
+	// MAC is the requesting client's hardware address, when known.
+	MAC net.HardwareAddr
+	// IP is the requesting client's address.
+	IP net.IP
+	// UserClass is the iPXE user-class option (e.g. "iPXE"), empty for
+	// the initial (non-iPXE) boot stage.
+	UserClass string
+	// Arch is the client architecture detected from the DHCP/TFTP
+	// request, e.g. "x86_64" or "aarch64".
+	Arch string
+}
+
+// ScriptResolver returns the patch bytes to apply to the binary requested by
+// req. Implementations should return a nil patch and a nil error when no
+// per-client override applies, in which case the server falls back to its
+// default patch.
+type ScriptResolver interface {
+	Resolve(ctx context.Context, req Request) ([]byte, error)
+}
+
+// ScriptResolverFunc adapts a function to a ScriptResolver.
+type ScriptResolverFunc func(ctx context.Context, req Request) ([]byte, error)
+
+// Resolve implements ScriptResolver.
+func (f ScriptResolverFunc) Resolve(ctx context.Context, req Request) ([]byte, error) {
+	return f(ctx, req)
+}
+
+// CachingResolver wraps a ScriptResolver and memoizes the resolved patch per
+// request, so repeated requests for the same binary from the same client
+// don't re-run the (potentially expensive, e.g. HTTP/gRPC) wrapped resolver
+// on every TFTP/HTTP request. It satisfies ScriptResolver itself, so it can
+// be nested or passed anywhere a ScriptResolver is expected.
+type CachingResolver struct {
+	next ScriptResolver
+
+	mu    sync.Mutex
+	cache map[string][]byte // keyed by requestKey(req)
+}
+
+// NewCachingResolver wraps next with a per-request cache.
+func NewCachingResolver(next ScriptResolver) *CachingResolver {
+	return &CachingResolver{
+		next:  next,
+		cache: map[string][]byte{},
+	}
+}
+
+// Resolve implements ScriptResolver. It checks the cache for a key derived
+// from req itself before ever calling the wrapped resolver, so a cache hit
+// costs nothing beyond the lookup; only a miss calls next.Resolve, and its
+// result is cached for the next request with the same key.
+func (c *CachingResolver) Resolve(ctx context.Context, req Request) ([]byte, error) {
+	key := requestKey(req)
+
+	c.mu.Lock()
+	if cached, ok := c.cache[key]; ok {
+		c.mu.Unlock()
+		return cached, nil
+	}
+	c.mu.Unlock()
+
+	patch, err := c.next.Resolve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[key] = patch
+	c.mu.Unlock()
+
+	return patch, nil
+}
+
+// requestKey derives a cache key from the parts of req that determine which
+// patch gets resolved, without needing to have resolved it first.
+func requestKey(req Request) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s", req.BinaryName, req.MAC, req.IP, req.UserClass, req.Arch)
+}
@@ -0,0 +1,45 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestCachingResolverCallsNextAtMostOncePerKey(t *testing.T) {
+	calls := 0
+	next := ScriptResolverFunc(func(_ context.Context, req Request) ([]byte, error) {
+		calls++
+		return []byte("patch-for-" + req.BinaryName), nil
+	})
+
+	c := NewCachingResolver(next)
+	req := Request{BinaryName: "undionly.kpxe", MAC: net.HardwareAddr{0, 1, 2, 3, 4, 5}}
+
+	for i := 0; i < 5; i++ {
+		patch, err := c.Resolve(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Resolve() error on call %d: %v", i, err)
+		}
+		if string(patch) != "patch-for-undionly.kpxe" {
+			t.Fatalf("Resolve() = %q, want %q", patch, "patch-for-undionly.kpxe")
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("next.Resolve called %d times, want 1", calls)
+	}
+
+	// A different request key must still reach next.
+	other := Request{BinaryName: "ipxe.efi", MAC: net.HardwareAddr{0, 1, 2, 3, 4, 5}}
+	if _, err := c.Resolve(context.Background(), other); err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("next.Resolve called %d times after a distinct key, want 2", calls)
+	}
+}
+
+func TestCachingResolverSatisfiesScriptResolver(t *testing.T) {
+	var _ ScriptResolver = (*CachingResolver)(nil)
+}
@@ -0,0 +1,32 @@
+package resolver
+
+import (
+	"context"
+
+	"github.com/rpardini/tinkerbell-ipxedust/binary"
+)
+
+// ResolvePatchedFile is the per-request integration point the original
+// request described: call it once per TFTP/HTTP request instead of
+// binary.PatchedFile directly. It resolves a per-client patch via resolver
+// (falling back to fallbackPatch, typically the server's configured global
+// patch, when resolver is nil or returns none) and applies it to
+// binary.Files[binaryName] via binary.PatchedFile, ready to stream back to
+// the client. The result is a per-request copy when a patch was applied,
+// and the stored content itself otherwise.
+func ResolvePatchedFile(ctx context.Context, r ScriptResolver, binaryName string, req Request, fallbackPatch []byte) ([]byte, error) {
+	req.BinaryName = binaryName
+
+	patch := fallbackPatch
+	if r != nil {
+		resolved, err := r.Resolve(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if resolved != nil {
+			patch = resolved
+		}
+	}
+
+	return binary.PatchedFile(binaryName, patch)
+}
@@ -0,0 +1,39 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rpardini/tinkerbell-ipxedust/binary"
+)
+
+func TestResolvePatchedFile(t *testing.T) {
+	const name = "resolver-serve-test.ipxe"
+	binary.SetFile(name, []byte("#!ipxe\n"))
+	t.Cleanup(func() { binary.SetFile(name, nil) })
+
+	// Unknown binary name propagates binary.ErrFileNotFound.
+	if _, err := ResolvePatchedFile(context.Background(), nil, "does-not-exist", Request{}, nil); !errors.Is(err, binary.ErrFileNotFound) {
+		t.Errorf("ResolvePatchedFile() for an unregistered name: got err %v, want ErrFileNotFound", err)
+	}
+
+	// No resolver configured: resolves the registered file without error.
+	if _, err := ResolvePatchedFile(context.Background(), nil, name, Request{}, nil); err != nil {
+		t.Errorf("ResolvePatchedFile() with no resolver: unexpected error %v", err)
+	}
+
+	// A resolver error propagates.
+	failing := ScriptResolverFunc(func(context.Context, Request) ([]byte, error) {
+		return nil, errors.New("boom")
+	})
+	if _, err := ResolvePatchedFile(context.Background(), failing, name, Request{}, nil); err == nil {
+		t.Error("ResolvePatchedFile() with a failing resolver: want error, got nil")
+	}
+
+	// A resolver that declines (nil, nil) still resolves the file fine.
+	noop := ScriptResolverFunc(func(context.Context, Request) ([]byte, error) { return nil, nil })
+	if _, err := ResolvePatchedFile(context.Background(), noop, name, Request{}, nil); err != nil {
+		t.Errorf("ResolvePatchedFile() with a no-op resolver: unexpected error %v", err)
+	}
+}
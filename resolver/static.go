@@ -0,0 +1,79 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// staticEntry is one row of a static resolver map file, keyed by the
+// requesting client's MAC address.
+type staticEntry struct {
+	MAC   string `json:"mac"`
+	Patch string `json:"patch"`
+}
+
+// StaticResolver resolves patches from an in-memory map loaded once from a
+// JSON file on disk, keyed by MAC address. It's the simplest ScriptResolver,
+// intended for small, mostly-static fleets where operators would otherwise
+// hand-edit a single global patch.
+type StaticResolver struct {
+	mu    sync.RWMutex
+	byMAC map[string][]byte
+	path  string
+}
+
+// NewStaticResolver loads path, a JSON file containing an array of
+// {"mac": "...", "patch": "..."} entries, into memory.
+func NewStaticResolver(path string) (*StaticResolver, error) {
+	r := &StaticResolver{path: path}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the map file from disk, replacing the in-memory entries.
+// Callers may wire this to SIGHUP so the map can be updated without a
+// restart.
+func (r *StaticResolver) Reload() error {
+	return r.reload()
+}
+
+func (r *StaticResolver) reload() error {
+	b, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("reading static resolver map %q: %w", r.path, err)
+	}
+
+	var entries []staticEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return fmt.Errorf("parsing static resolver map %q: %w", r.path, err)
+	}
+
+	byMAC := make(map[string][]byte, len(entries))
+	for _, e := range entries {
+		byMAC[e.MAC] = []byte(e.Patch)
+	}
+
+	r.mu.Lock()
+	r.byMAC = byMAC
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Resolve implements ScriptResolver. It returns a nil patch when req.MAC has
+// no entry in the map, so the server falls back to its default patch.
+func (r *StaticResolver) Resolve(_ context.Context, req Request) ([]byte, error) {
+	if req.MAC == nil {
+		return nil, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.byMAC[req.MAC.String()], nil
+}
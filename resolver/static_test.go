@@ -0,0 +1,44 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStaticResolverResolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "map.json")
+	body := `[{"mac": "aa:bb:cc:dd:ee:ff", "patch": "#!ipxe\nchain http://example/boot.ipxe\n"}]`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("writing static map: %v", err)
+	}
+
+	r, err := NewStaticResolver(path)
+	if err != nil {
+		t.Fatalf("NewStaticResolver() error: %v", err)
+	}
+
+	mac, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	patch, err := r.Resolve(context.Background(), Request{MAC: mac})
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	if string(patch) != "#!ipxe\nchain http://example/boot.ipxe\n" {
+		t.Errorf("Resolve() = %q, want the configured patch", patch)
+	}
+
+	unknownMAC, _ := net.ParseMAC("11:22:33:44:55:66")
+	patch, err = r.Resolve(context.Background(), Request{MAC: unknownMAC})
+	if err != nil {
+		t.Fatalf("Resolve() error for unknown MAC: %v", err)
+	}
+	if patch != nil {
+		t.Errorf("Resolve() for unknown MAC = %q, want nil", patch)
+	}
+
+	if _, err := r.Resolve(context.Background(), Request{}); err != nil {
+		t.Errorf("Resolve() with no MAC should not error, got %v", err)
+	}
+}